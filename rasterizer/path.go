@@ -0,0 +1,259 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type pathPoint struct{ x, y float32 }
+
+// Rasterizer is a 2D vector-graphics path builder layered on top of the
+// triangle rasterizer: MoveTo/LineTo/QuadTo/CubeTo/ClosePath describe one or
+// more closed subpaths, curves are flattened into line segments, and Draw
+// fan-triangulates each subpath and runs every triangle through Rasterize.
+type Rasterizer struct {
+	tolerance float32
+	subpaths  [][]pathPoint
+	cur       []pathPoint
+
+	curX, curY     float32
+	startX, startY float32
+}
+
+// NewRasterizer returns a path Rasterizer that flattens curves to within
+// tolerance pixels of the true curve. A tolerance of 0 uses a sane default.
+func NewRasterizer(tolerance float32) *Rasterizer {
+	if tolerance <= 0 {
+		tolerance = 0.25
+	}
+	return &Rasterizer{tolerance: tolerance}
+}
+
+// MoveTo starts a new subpath at (x, y), closing off whatever subpath was
+// being built before it.
+func (r *Rasterizer) MoveTo(x, y float32) {
+	r.endSubpath()
+	r.cur = append(r.cur, pathPoint{x, y})
+	r.curX, r.curY = x, y
+	r.startX, r.startY = x, y
+}
+
+// LineTo appends a straight line segment to the current subpath.
+func (r *Rasterizer) LineTo(x, y float32) {
+	r.cur = append(r.cur, pathPoint{x, y})
+	r.curX, r.curY = x, y
+}
+
+// QuadTo appends a quadratic Bezier curve, flattened into line segments.
+func (r *Rasterizer) QuadTo(cx, cy, x, y float32) {
+	r.flattenQuad(r.curX, r.curY, cx, cy, x, y, 0)
+	r.curX, r.curY = x, y
+}
+
+// CubeTo appends a cubic Bezier curve, flattened into line segments.
+func (r *Rasterizer) CubeTo(c0x, c0y, c1x, c1y, x, y float32) {
+	r.flattenCube(r.curX, r.curY, c0x, c0y, c1x, c1y, x, y, 0)
+	r.curX, r.curY = x, y
+}
+
+// ClosePath closes the current subpath back to its starting point.
+func (r *Rasterizer) ClosePath() {
+	if len(r.cur) > 0 {
+		r.cur = append(r.cur, pathPoint{r.startX, r.startY})
+	}
+}
+
+func (r *Rasterizer) endSubpath() {
+	if len(r.cur) >= 3 {
+		r.subpaths = append(r.subpaths, r.cur)
+	}
+	r.cur = nil
+}
+
+const maxFlattenDepth = 16
+
+func (r *Rasterizer) flattenQuad(x0, y0, cx, cy, x1, y1 float32, depth int) {
+	if depth >= maxFlattenDepth || quadFlatEnough(x0, y0, cx, cy, x1, y1, r.tolerance) {
+		r.cur = append(r.cur, pathPoint{x1, y1})
+		return
+	}
+
+	x01, y01 := mid(x0, y0, cx, cy)
+	x12, y12 := mid(cx, cy, x1, y1)
+	xm, ym := mid(x01, y01, x12, y12)
+
+	r.flattenQuad(x0, y0, x01, y01, xm, ym, depth+1)
+	r.flattenQuad(xm, ym, x12, y12, x1, y1, depth+1)
+}
+
+func (r *Rasterizer) flattenCube(x0, y0, c0x, c0y, c1x, c1y, x1, y1 float32, depth int) {
+	if depth >= maxFlattenDepth || cubeFlatEnough(x0, y0, c0x, c0y, c1x, c1y, x1, y1, r.tolerance) {
+		r.cur = append(r.cur, pathPoint{x1, y1})
+		return
+	}
+
+	x01, y01 := mid(x0, y0, c0x, c0y)
+	x12, y12 := mid(c0x, c0y, c1x, c1y)
+	x23, y23 := mid(c1x, c1y, x1, y1)
+	x012, y012 := mid(x01, y01, x12, y12)
+	x123, y123 := mid(x12, y12, x23, y23)
+	xm, ym := mid(x012, y012, x123, y123)
+
+	r.flattenCube(x0, y0, x01, y01, x012, y012, xm, ym, depth+1)
+	r.flattenCube(xm, ym, x123, y123, x23, y23, x1, y1, depth+1)
+}
+
+func mid(ax, ay, bx, by float32) (float32, float32) {
+	return (ax + bx) / 2, (ay + by) / 2
+}
+
+func absF32(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// roundF32 rounds f to the nearest integer, rounding half up. Go's int(f)
+// truncates toward zero instead, which would round -0.7 to 0 rather than
+// the correct -1.
+func roundF32(f float32) int {
+	return int(math.Floor(float64(f) + 0.5))
+}
+
+// quadFlatEnough tests the control point's distance from the chord x0y0-x1y1
+// against tol, the standard flatness test for curve subdivision.
+func quadFlatEnough(x0, y0, cx, cy, x1, y1, tol float32) bool {
+	dx, dy := x1-x0, y1-y0
+	d := absF32(dx*(y0-cy) - dy*(x0-cx))
+	return d*d <= tol*tol*(dx*dx+dy*dy)
+}
+
+func cubeFlatEnough(x0, y0, c0x, c0y, c1x, c1y, x1, y1, tol float32) bool {
+	dx, dy := x1-x0, y1-y0
+	d1 := absF32(dx*(y0-c0y) - dy*(x0-c0x))
+	d2 := absF32(dx*(y0-c1y) - dy*(x0-c1x))
+	return (d1+d2)*(d1+d2) <= tol*tol*(dx*dx+dy*dy)
+}
+
+// triangulateFan fans a polygon out from its first vertex. This only
+// produces a correct fill for convex (or vertex-0-star-shaped) polygons:
+// a concave outline like the letter "S" will triangulate into overlapping
+// or inverted triangles, and a multi-subpath glyph with a counter (like
+// "O") fills each subpath independently rather than subtracting one from
+// the other, so it renders as a solid disc instead of a ring. Fan
+// triangulation was chosen for its simplicity; general concave fills or a
+// winding-rule combine across subpaths aren't implemented yet.
+func triangulateFan(poly []pathPoint) [][3]pathPoint {
+	if len(poly) < 3 {
+		return nil
+	}
+
+	tris := make([][3]pathPoint, 0, len(poly)-2)
+	for i := 1; i < len(poly)-1; i++ {
+		tris = append(tris, [3]pathPoint{poly[0], poly[i], poly[i+1]})
+	}
+	return tris
+}
+
+func polyBounds(poly []pathPoint) (minX, minY, maxX, maxY float32) {
+	minX, minY = poly[0].x, poly[0].y
+	maxX, maxY = minX, minY
+
+	for _, p := range poly[1:] {
+		if p.x < minX {
+			minX = p.x
+		}
+		if p.y < minY {
+			minY = p.y
+		}
+		if p.x > maxX {
+			maxX = p.x
+		}
+		if p.y > maxY {
+			maxY = p.y
+		}
+	}
+	return
+}
+
+// blendSrcOver composites src over dst using src's own alpha channel,
+// i.e. the Porter-Duff "over" operator.
+func blendSrcOver(dst, src color.Color) color.Color {
+	sr, sg, sb, sa := src.RGBA()
+	dr, dg, db, da := dst.RGBA()
+
+	inv := 0xffff - sa
+
+	return color.RGBA64{
+		R: uint16(sr + dr*inv/0xffff),
+		G: uint16(sg + dg*inv/0xffff),
+		B: uint16(sb + db*inv/0xffff),
+		A: uint16(sa + da*inv/0xffff),
+	}
+}
+
+// Draw fills every subpath built so far into dst, sampling src as either a
+// solid color (when src is an *image.Uniform) or a texture mapped across
+// each subpath's bounding box, and clears the path afterwards. With
+// op == draw.Over, both the solid and textured cases are alpha-blended over
+// dst using the source color's own alpha (src.At's alpha for textures);
+// any other op overwrites dst outright.
+func (r *Rasterizer) Draw(dst draw.Image, src image.Image, op draw.Op) {
+	r.endSubpath()
+	defer func() { r.subpaths = nil }()
+
+	uniform, solid := src.(*image.Uniform)
+
+	for _, poly := range r.subpaths {
+		tris := triangulateFan(poly)
+		if tris == nil {
+			continue
+		}
+
+		var ps PixelShader
+		if solid {
+			col := uniform.C
+			ps = func(x, y int, u, v float32) {
+				if op == draw.Over {
+					dst.Set(x, y, blendSrcOver(dst.At(x, y), col))
+				} else {
+					dst.Set(x, y, col)
+				}
+			}
+		} else {
+			ps = func(x, y int, u, v float32) {
+				col := sampleTexture(src, u, v)
+				if op == draw.Over {
+					dst.Set(x, y, blendSrcOver(dst.At(x, y), col))
+				} else {
+					dst.Set(x, y, col)
+				}
+			}
+		}
+
+		minX, minY, maxX, maxY := polyBounds(poly)
+		texW, texH := maxX-minX, maxY-minY
+
+		for _, t := range tris {
+			x0, y0 := roundF32(t[0].x), roundF32(t[0].y)
+			x1, y1 := roundF32(t[1].x), roundF32(t[1].y)
+			x2, y2 := roundF32(t[2].x), roundF32(t[2].y)
+
+			var u0, v0, u1, v1, u2, v2 float32
+			if !solid && texW != 0 && texH != 0 {
+				u0, v0 = (t[0].x-minX)/texW, (t[0].y-minY)/texH
+				u1, v1 = (t[1].x-minX)/texW, (t[1].y-minY)/texH
+				u2, v2 = (t[2].x-minX)/texW, (t[2].y-minY)/texH
+			}
+
+			Rasterize(ps, x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2)
+		}
+	}
+}
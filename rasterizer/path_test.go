@@ -0,0 +1,175 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+// TestRoundF32Negative covers a regression where Draw used int(x+0.5) to
+// round path vertices to pixels, which truncates toward zero and so
+// rounds e.g. -0.7 to 0 instead of -1.
+func TestRoundF32Negative(t *testing.T) {
+	cases := []struct {
+		in   float32
+		want int
+	}{
+		{0.4, 0},
+		{0.5, 1},
+		{2.9, 3},
+		{-0.4, 0},
+		{-0.7, -1},
+		{-2.5, -2},
+	}
+
+	for _, c := range cases {
+		if got := roundF32(c.in); got != c.want {
+			t.Errorf("roundF32(%v) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+// TestDrawFillsSolidQuad covers the end-to-end path: build a square
+// subpath, Draw it with a solid color, and check that it lands on the
+// pixels it should (and nowhere else).
+func TestDrawFillsSolidQuad(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	red := color.RGBA{255, 0, 0, 255}
+
+	r := NewRasterizer(0)
+	r.MoveTo(5, 5)
+	r.LineTo(15, 5)
+	r.LineTo(15, 15)
+	r.LineTo(5, 15)
+	r.ClosePath()
+	r.Draw(dst, image.NewUniform(red), draw.Src)
+
+	if got := dst.RGBAAt(10, 10); got != red {
+		t.Errorf("interior pixel (10,10) = %v, want %v", got, red)
+	}
+	if got := dst.RGBAAt(1, 1); got != (color.RGBA{}) {
+		t.Errorf("exterior pixel (1,1) = %v, want zero value", got)
+	}
+}
+
+// TestDrawOverBlendsTexturedAlpha covers a regression where Draw's textured
+// branch always hard-overwrote dst via NewDefaultShader regardless of op,
+// ignoring both op and the texture's own alpha channel. With op == draw.Over
+// and a half-transparent texture, the result must be a blend of the texture
+// color and the opaque background, not the pure texture color.
+func TestDrawOverBlendsTexturedAlpha(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+
+	halfBlue := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	halfBlue.Set(0, 0, color.RGBA{0, 0, 255, 128})
+
+	r := NewRasterizer(0)
+	r.MoveTo(5, 5)
+	r.LineTo(15, 5)
+	r.LineTo(15, 15)
+	r.LineTo(5, 15)
+	r.ClosePath()
+	r.Draw(dst, halfBlue, draw.Over)
+
+	got := dst.RGBAAt(10, 10)
+	if got.B == 255 && got.R == 0 {
+		t.Errorf("pixel (10,10) = %v, texture clobbered the background instead of blending over it", got)
+	}
+	if got.R == 0 {
+		t.Errorf("pixel (10,10) = %v, expected some red left over from the background", got)
+	}
+	if got.B == 0 {
+		t.Errorf("pixel (10,10) = %v, expected some blue blended in from the texture", got)
+	}
+}
+
+// TestFlattenQuadWithinTolerance covers that QuadTo's recursive subdivision
+// actually stays within `tolerance` of the true quadratic Bezier curve,
+// by sampling the analytic curve and checking every sample lands close to
+// the flattened polyline.
+func TestFlattenQuadWithinTolerance(t *testing.T) {
+	const tolerance = float32(0.5)
+
+	r := NewRasterizer(tolerance)
+	r.MoveTo(0, 0)
+	r.QuadTo(50, 100, 100, 0)
+
+	poly := r.cur
+	if len(poly) < 3 {
+		t.Fatalf("expected the curve to subdivide into several segments, got %d points", len(poly))
+	}
+
+	const samples = 100
+	const slack = 0.5 // the flatness test bounds chord deviation, not polyline distance exactly
+	for i := 0; i <= samples; i++ {
+		tt := float32(i) / samples
+		bx := sq(1-tt)*0 + 2*(1-tt)*tt*50 + sq(tt)*100
+		by := sq(1-tt)*0 + 2*(1-tt)*tt*100 + sq(tt)*0
+
+		if minDistToPolyline(bx, by, poly) > tolerance+slack {
+			t.Errorf("curve point (%v,%v) at t=%v is farther than tolerance+slack from the flattened polyline", bx, by, tt)
+		}
+	}
+}
+
+func sq(f float32) float32 { return f * f }
+
+func minDistToPolyline(px, py float32, poly []pathPoint) float32 {
+	min := float32(math.MaxFloat32)
+	prev := pathPoint{0, 0}
+	for i, p := range poly {
+		if i > 0 {
+			if d := distToSegment(px, py, prev.x, prev.y, p.x, p.y); d < min {
+				min = d
+			}
+		}
+		prev = p
+	}
+	return min
+}
+
+func distToSegment(px, py, ax, ay, bx, by float32) float32 {
+	dx, dy := bx-ax, by-ay
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return float32(math.Hypot(float64(px-ax), float64(py-ay)))
+	}
+
+	t := ((px-ax)*dx + (py-ay)*dy) / lenSq
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+
+	cx, cy := ax+t*dx, ay+t*dy
+	return float32(math.Hypot(float64(px-cx), float64(py-cy)))
+}
+
+// TestTriangulateFanKnownPolygon covers that triangulateFan fans a simple
+// convex polygon out from its first vertex in the expected order.
+func TestTriangulateFanKnownPolygon(t *testing.T) {
+	square := []pathPoint{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+
+	got := triangulateFan(square)
+	want := [][3]pathPoint{
+		{{0, 0}, {10, 0}, {10, 10}},
+		{{0, 0}, {10, 10}, {0, 10}},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("triangulateFan(square) produced %d triangles, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("triangle %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,309 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// CoverageShader is like PixelShader but also receives the fractional pixel
+// coverage computed by RasterizeAA, so callers can composite with
+// draw.Over-style blending instead of a hard overwrite.
+type CoverageShader func(x, y int, u, v, coverage float32)
+
+// NewDefaultCoverageShader returns a CoverageShader that alpha-blends the
+// texture sample over whatever is already in target, using coverage as the
+// blend weight.
+func NewDefaultCoverageShader(target draw.Image, texture image.Image) CoverageShader {
+	textureSize := texture.Bounds().Max
+	maxX := textureSize.X - 1
+	maxY := textureSize.Y - 1
+
+	return func(x, y int, u, v, coverage float32) {
+		if coverage <= 0 {
+			return
+		}
+
+		tx := int(u * float32(maxX))
+		ty := int(v * float32(maxY))
+
+		if tx > maxX {
+			tx = maxX
+		} else if tx < 0 {
+			tx = 0
+		}
+
+		if ty > maxY {
+			ty = maxY
+		} else if ty < 0 {
+			ty = 0
+		}
+
+		if coverage >= 1 {
+			target.Set(x, y, texture.At(tx, ty))
+			return
+		}
+
+		target.Set(x, y, blendOver(target.At(x, y), texture.At(tx, ty), coverage))
+	}
+}
+
+// blendOver alpha-blends src over dst using a draw.Over-style composite,
+// weighted by coverage instead of src's own alpha channel.
+func blendOver(dst, src color.Color, coverage float32) color.Color {
+	sr, sg, sb, _ := src.RGBA()
+	dr, dg, db, da := dst.RGBA()
+
+	a := uint32(coverage * 0xffff)
+	inv := 0xffff - a
+
+	return color.RGBA64{
+		R: uint16((sr*a + dr*inv) / 0xffff),
+		G: uint16((sg*a + dg*inv) / 0xffff),
+		B: uint16((sb*a + db*inv) / 0xffff),
+		A: uint16((0xffff*a + da*inv) / 0xffff),
+	}
+}
+
+// Triangle is one triangle of a textured mesh, using the same vertex and UV
+// layout as Rasterize's parameters. It's the unit RasterizeMeshAA composes
+// several of at once.
+type Triangle struct {
+	X0, Y0, X1, Y1, X2, Y2 int
+	U0, V0, U1, V1, U2, V2 float32
+}
+
+// RasterizeAA renders a single triangle with coverage-based anti-aliasing.
+// It is a thin wrapper around RasterizeMeshAA - see that doc comment for how
+// coverage is computed. Note that calling RasterizeAA once per triangle of a
+// multi-triangle shape (e.g. a fan from the Path API) will still show a
+// faint seam along edges shared between triangles, because each call
+// accumulates and resolves its own triangle's coverage in isolation; use
+// RasterizeMeshAA to anti-alias a whole mesh seamlessly.
+func RasterizeAA(ps CoverageShader, x0, y0, x1, y1, x2, y2 int, u0, v0, u1, v1, u2, v2 float32) {
+	RasterizeMeshAA(ps, []Triangle{{x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2}})
+}
+
+// RasterizeMeshAA is the coverage anti-aliased sibling of Rasterize for a
+// whole mesh of triangles. Instead of a single inside/outside test per pixel
+// it accumulates the signed area every triangle edge in the mesh sweeps
+// through the cells of one scratch buffer sized to the mesh's combined
+// bounding box - the same accumulate-then-prefix-sum technique used by
+// golang.org/x/image/vector for glyph rendering. Accumulating the whole mesh
+// before the prefix sum, rather than one triangle at a time, is what makes
+// edges shared between adjacent triangles cancel out instead of doubling up
+// into a visible seam. A horizontal prefix sum over each row turns the
+// per-edge deltas into per-pixel coverage in [0,1], which is then used to
+// weight the pixel shader call; the shaded triangle is whichever one
+// contains the pixel, falling back to the closest triangle for the
+// anti-aliased pixels that sit just outside every triangle's exact bounds.
+func RasterizeMeshAA(ps CoverageShader, tris []Triangle) {
+	if len(tris) == 0 {
+		return
+	}
+
+	minX, maxX := tris[0].X0, tris[0].X0
+	minY, maxY := tris[0].Y0, tris[0].Y0
+	for _, t := range tris {
+		if tMinX := min3(t.X0, t.X1, t.X2); tMinX < minX {
+			minX = tMinX
+		}
+		if tMaxX := max3(t.X0, t.X1, t.X2); tMaxX > maxX {
+			maxX = tMaxX
+		}
+		if tMinY := min3(t.Y0, t.Y1, t.Y2); tMinY < minY {
+			minY = tMinY
+		}
+		if tMaxY := max3(t.Y0, t.Y1, t.Y2); tMaxY > maxY {
+			maxY = tMaxY
+		}
+	}
+	maxX++
+	maxY++
+
+	w := maxX - minX
+	h := maxY - minY
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	acc := make([]float32, w*h)
+
+	for _, t := range tris {
+		fx0, fy0 := float32(t.X0-minX), float32(t.Y0-minY)
+		fx1, fy1 := float32(t.X1-minX), float32(t.Y1-minY)
+		fx2, fy2 := float32(t.X2-minX), float32(t.Y2-minY)
+
+		accumulateEdge(acc, w, h, fx0, fy0, fx1, fy1)
+		accumulateEdge(acc, w, h, fx1, fy1, fx2, fy2)
+		accumulateEdge(acc, w, h, fx2, fy2, fx0, fy0)
+	}
+
+	// Horizontal prefix-sum: the deltas left behind by accumulateEdge only
+	// describe how coverage changes when crossing an edge, so a running sum
+	// across each scanline turns them into absolute coverage.
+	for y := 0; y < h; y++ {
+		row := y * w
+		var sum float32
+		for x := 0; x < w; x++ {
+			sum += acc[row+x]
+			cov := sum
+			if cov < 0 {
+				cov = -cov
+			}
+			if cov > 1 {
+				cov = 1
+			}
+			if cov == 0 {
+				continue
+			}
+
+			pu, pv := meshUV(float32(x)+0.5, float32(y)+0.5, tris, minX, minY)
+			ps(minX+x, minY+y, pu, pv, cov)
+		}
+	}
+}
+
+// accumulateEdge walks the edge a->b in y and, for every scanline row it
+// crosses, distributes the signed area it sweeps through that row between
+// the two buffer cells straddling the edge's crossing point.
+func accumulateEdge(buf []float32, w, h int, ax, ay, bx, by float32) {
+	if ay == by {
+		return
+	}
+
+	sign := float32(1)
+	if ay > by {
+		ax, ay, bx, by = bx, by, ax, ay
+		sign = -1
+	}
+
+	dxdy := (bx - ax) / (by - ay)
+
+	y0 := int(math.Floor(float64(ay)))
+	y1 := int(math.Ceil(float64(by)))
+	if y0 < 0 {
+		y0 = 0
+	}
+	if y1 > h {
+		y1 = h
+	}
+
+	for y := y0; y < y1; y++ {
+		segTop := float32(y)
+		if ay > segTop {
+			segTop = ay
+		}
+		segBot := float32(y + 1)
+		if by < segBot {
+			segBot = by
+		}
+
+		dy := segBot - segTop
+		if dy <= 0 {
+			continue
+		}
+
+		xMid := ax + (segTop+segBot-2*ay)*0.5*dxdy
+
+		ix := int(math.Floor(float64(xMid)))
+		frac := xMid - float32(ix)
+
+		row := y * w
+		if ix >= 0 && ix < w {
+			buf[row+ix] += sign * dy * (1 - frac)
+		} else if ix < 0 {
+			buf[row] += sign * dy
+		}
+		if ix+1 >= 0 && ix+1 < w {
+			buf[row+ix+1] += sign * dy * frac
+		}
+	}
+}
+
+// meshUV finds whichever triangle in tris contains (px, py) - in buffer-
+// local coordinates, i.e. already offset by (minX, minY) - and returns its
+// barycentric-interpolated UV. If no triangle's bounds quite contain the
+// point (common for the partially-covered pixels right at a mesh's outer
+// edge), it falls back to the triangle whose barycentric weights are
+// closest to valid.
+func meshUV(px, py float32, tris []Triangle, minX, minY int) (float32, float32) {
+	var bestU, bestV, bestScore float32
+	haveBest := false
+
+	for _, t := range tris {
+		x0, y0 := float32(t.X0-minX), float32(t.Y0-minY)
+		x1, y1 := float32(t.X1-minX), float32(t.Y1-minY)
+		x2, y2 := float32(t.X2-minX), float32(t.Y2-minY)
+
+		w0, w1, w2, ok := baryWeights(px, py, x0, y0, x1, y1, x2, y2)
+		u := w0*t.U0 + w1*t.U1 + w2*t.U2
+		v := w0*t.V0 + w1*t.V1 + w2*t.V2
+
+		if ok {
+			return u, v
+		}
+
+		score := negAmount(w0) + negAmount(w1) + negAmount(w2)
+		if !haveBest || score < bestScore {
+			haveBest = true
+			bestScore = score
+			bestU, bestV = u, v
+		}
+	}
+
+	return bestU, bestV
+}
+
+// baryWeights returns the barycentric weights of (px, py) against the
+// triangle (x0,y0)-(x1,y1)-(x2,y2), along with whether the point actually
+// falls inside the triangle (all weights non-negative, within a small
+// tolerance for points that sit exactly on an edge).
+func baryWeights(px, py, x0, y0, x1, y1, x2, y2 float32) (w0, w1, w2 float32, ok bool) {
+	denom := (y1-y2)*(x0-x2) + (x2-x1)*(y0-y2)
+	if denom == 0 {
+		return 1, 0, 0, false
+	}
+
+	w0 = ((y1-y2)*(px-x2) + (x2-x1)*(py-y2)) / denom
+	w1 = ((y2-y0)*(px-x2) + (x0-x2)*(py-y2)) / denom
+	w2 = 1 - w0 - w1
+
+	const eps = 1e-3
+	ok = w0 >= -eps && w1 >= -eps && w2 >= -eps
+	return
+}
+
+func negAmount(f float32) float32 {
+	if f >= 0 {
+		return 0
+	}
+	return -f
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+func max3(a, b, c int) int {
+	m := a
+	if b > m {
+		m = b
+	}
+	if c > m {
+		m = c
+	}
+	return m
+}
@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "testing"
+
+// TestRasterizeMeshAANoSeam covers a regression where compositing a square
+// as two triangles sharing a diagonal, one RasterizeAA call per triangle,
+// left the shared diagonal partially transparent: each triangle's coverage
+// buffer only ever saw its own edges, so the two triangles' contributions
+// along the shared edge didn't cancel out. Accumulating both triangles into
+// RasterizeMeshAA's single buffer before the prefix sum fixes that.
+func TestRasterizeMeshAANoSeam(t *testing.T) {
+	const size = 20
+	cov := make([]float32, size*size)
+
+	ps := func(x, y int, u, v, coverage float32) {
+		cov[y*size+x] += coverage
+	}
+
+	tris := []Triangle{
+		{X0: 0, Y0: 0, X1: size, Y1: 0, X2: 0, Y2: size},
+		{X0: size, Y0: 0, X1: size, Y1: size, X2: 0, Y2: size},
+	}
+	RasterizeMeshAA(ps, tris)
+
+	for y := 1; y < size-1; y++ {
+		for x := 1; x < size-1; x++ {
+			if c := cov[y*size+x]; c < 0.999 {
+				t.Fatalf("interior pixel (%d,%d) under-covered: got %v, want ~1", x, y, c)
+			}
+		}
+	}
+}
+
+func TestRasterizeAASingleTriangleCoversInterior(t *testing.T) {
+	var hits int
+	ps := func(x, y int, u, v, coverage float32) {
+		if coverage > 0.999 {
+			hits++
+		}
+	}
+
+	RasterizeAA(ps, 0, 0, 20, 0, 0, 20, 0, 0, 1, 0, 0, 1)
+	if hits == 0 {
+		t.Fatal("expected at least one fully-covered interior pixel")
+	}
+}
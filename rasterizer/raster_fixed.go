@@ -0,0 +1,204 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+// fixed26_6 is a 26.6 fixed-point number: 26 bits of integer part, 6 bits
+// of fractional part, stored in an int32. It's precise enough for pixel
+// coordinates, which is all it's used for.
+type fixed26_6 int32
+
+const fixedShift = 6
+
+func floatToFixed(f float32) fixed26_6 {
+	return fixed26_6(f * (1 << fixedShift))
+}
+
+func (f fixed26_6) toFloat32() float32 {
+	return float32(f) / (1 << fixedShift)
+}
+
+func (f fixed26_6) toFloat64() float64 {
+	return float64(f) / (1 << fixedShift)
+}
+
+func fixedDiv(a, b fixed26_6) fixed26_6 {
+	if b == 0 {
+		return 0
+	}
+	return fixed26_6((int64(a) << fixedShift) / int64(b))
+}
+
+// fixedUV is a 16.16 fixed-point number, stored in an int64 to leave plenty
+// of headroom for accumulation. u and v are normalized to [0, 1], so 6
+// fractional bits (enough for pixel coordinates) would round per-row
+// gradients that are a small fraction of a texel down to zero; 16 bits
+// keeps that from happening.
+type fixedUV int64
+
+const uvShift = 16
+
+func floatToFixedUV(f float32) fixedUV {
+	return fixedUV(f * (1 << uvShift))
+}
+
+func (f fixedUV) toFloat32() float32 {
+	return float32(f) / (1 << uvShift)
+}
+
+func (f fixedUV) toFloat64() float64 {
+	return float64(f) / (1 << uvShift)
+}
+
+func floatToFixedUV64(f float64) fixedUV {
+	return fixedUV(f * (1 << uvShift))
+}
+
+// fixedUVPerRow divides a fixedUV delta by a plain scanline count, which is
+// how every per-triangle u/v gradient below is derived.
+func fixedUVPerRow(a fixedUV, rows int) fixedUV {
+	if rows == 0 {
+		return 0
+	}
+	return fixedUV(int64(a) / int64(rows))
+}
+
+// rasterizeFixed is the fixed-point backend. It mirrors rasterizeFloat step
+// for step, but walks x in 26.6 fixed-point and u, v in 16.16 fixed-point
+// instead of float32. Rasterize only dispatches here when the triangle's
+// bounding box stays within fixedPointMathThreshold, since each 26.6 x slope
+// is truncated to 1/64 pixel per scanline and that error adds up over
+// taller triangles; benchmarking hasn't shown this backend to be any faster
+// than rasterizeFloat, so the split exists for that precision limit, not for
+// performance.
+func rasterizeFixed(ps PixelShader, x0, y0, x1, y1, x2, y2 int, u0, v0, u1, v1, u2, v2 float32) {
+	multiSwap(&x0, &y0, &x1, &y1, &x2, &y2, &u0, &v0, &u1, &v1, &u2, &v2)
+
+	fx0, fx1, fx2 := floatToFixed(float32(x0)), floatToFixed(float32(x1)), floatToFixed(float32(x2))
+	fu0, fu1, fu2 := floatToFixedUV(u0), floatToFixedUV(u1), floatToFixedUV(u2)
+	fv0, fv1, fv2 := floatToFixedUV(v0), floatToFixedUV(v1), floatToFixedUV(v2)
+
+	dxdy1 := fx2 - fx0
+	dxdu1 := fu2 - fu0
+	dxdv1 := fv2 - fv0
+
+	dxdy2 := fx1 - fx0
+	dxdu2 := fu1 - fu0
+	dxdv2 := fv1 - fv0
+
+	var (
+		sdx, edx                   fixed26_6
+		sdu, sdv, edu, edv, pu, pv fixedUV
+	)
+
+	if y2-y0 != 0 {
+		dxdy1 = fixedDiv(dxdy1, floatToFixed(float32(y2-y0)))
+		dxdu1 = fixedUVPerRow(dxdu1, y2-y0)
+		dxdv1 = fixedUVPerRow(dxdv1, y2-y0)
+	}
+
+	if y1-y0 != 0 {
+		dxdy2 = fixedDiv(dxdy2, floatToFixed(float32(y1-y0)))
+		dxdu2 = fixedUVPerRow(dxdu2, y1-y0)
+		dxdv2 = fixedUVPerRow(dxdv2, y1-y0)
+	}
+
+	var (
+		dxldy, dxrdy               fixed26_6
+		dxldu, dxrdu, dxldv, dxrdv fixedUV
+	)
+
+	if dxdy1 < dxdy2 {
+		dxldy, dxrdy = dxdy1, dxdy2
+		dxldu, dxrdu = dxdu1, dxdu2
+		dxldv, dxrdv = dxdv1, dxdv2
+	} else {
+		dxldy, dxrdy = dxdy2, dxdy1
+		dxldu, dxrdu = dxdu2, dxdu1
+		dxldv, dxrdv = dxdv2, dxdv1
+	}
+
+	sdx, sdu, sdv = fx0, fu0, fv0
+	edx, edu, edv = fx0, fu0, fv0
+
+	var pDeltaU, pDeltaV fixedUV
+
+	for y := y0; y <= y2; y++ {
+		if span := (edx - sdx).toFloat64(); span != 0 {
+			pDeltaU = floatToFixedUV64((edu - sdu).toFloat64() / span)
+			pDeltaV = floatToFixedUV64((edv - sdv).toFloat64() / span)
+		} else {
+			pDeltaU, pDeltaV = 0, 0
+		}
+
+		pu = sdu
+		pv = sdv
+
+		for x := int(sdx >> fixedShift); x <= int(edx>>fixedShift); x++ {
+			ps(x, y, pu.toFloat32(), pv.toFloat32())
+			pu += pDeltaU
+			pv += pDeltaV
+		}
+
+		sdx += dxldy
+		sdu += dxldu
+		sdv += dxldv
+		edx += dxrdy
+		edu += dxrdu
+		edv += dxrdv
+	}
+
+	// Render bottom part of triangle.
+
+	if dxdy1 < dxdy2 {
+		dxldy = fx1 - fx2
+		dxldu = fu1 - fu2
+		dxldv = fv1 - fv2
+
+		if y1-y2 != 0 {
+			dxldy = fixedDiv(dxldy, floatToFixed(float32(y1-y2)))
+			dxldu = fixedUVPerRow(dxldu, y1-y2)
+			dxldv = fixedUVPerRow(dxldv, y1-y2)
+		}
+
+		sdx, sdu, sdv = fx2, fu2, fv2
+	} else {
+		dxrdy = fx1 - fx2
+		dxrdu = fu1 - fu2
+		dxrdv = fv1 - fv2
+
+		if y1-y2 != 0 {
+			dxrdy = fixedDiv(dxrdy, floatToFixed(float32(y1-y2)))
+			dxrdu = fixedUVPerRow(dxrdu, y1-y2)
+			dxrdv = fixedUVPerRow(dxrdv, y1-y2)
+		}
+
+		edx, edu, edv = fx2, fu2, fv2
+	}
+
+	for y := y2; y <= y1; y++ {
+		if span := (edx - sdx).toFloat64(); span != 0 {
+			pDeltaU = floatToFixedUV64((edu - sdu).toFloat64() / span)
+			pDeltaV = floatToFixedUV64((edv - sdv).toFloat64() / span)
+		} else {
+			pDeltaU, pDeltaV = 0, 0
+		}
+
+		pu = sdu
+		pv = sdv
+
+		for x := int(sdx >> fixedShift); x <= int(edx>>fixedShift); x++ {
+			ps(x, y, pu.toFloat32(), pv.toFloat32())
+			pu += pDeltaU
+			pv += pDeltaV
+		}
+
+		sdx += dxldy
+		sdu += dxldu
+		sdv += dxldv
+		edx += dxrdy
+		edu += dxrdu
+		edv += dxrdv
+	}
+}
@@ -0,0 +1,207 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+// multiSwapPersp is multiSwap extended with a third per-vertex attribute
+// (w), used by RasterizePerspective to sort vertices by y while keeping
+// u, v and w together.
+func multiSwapPersp(x0, y0, x1, y1, x2, y2 *int, u0, v0, w0, u1, v1, w1, u2, v2, w2 *float32) {
+	if *y1 < *y0 {
+		swapInt(y1, y0)
+		swapInt(x1, x0)
+		swapFloat32(u1, u0)
+		swapFloat32(v1, v0)
+		swapFloat32(w1, w0)
+	}
+
+	if *y2 < *y0 {
+		swapInt(y2, y0)
+		swapInt(x2, x0)
+		swapFloat32(u2, u0)
+		swapFloat32(v2, v0)
+		swapFloat32(w2, w0)
+	}
+
+	if *y1 < *y2 {
+		swapInt(y2, y1)
+		swapInt(x2, x1)
+		swapFloat32(u2, u1)
+		swapFloat32(v2, v1)
+		swapFloat32(w2, w1)
+	}
+}
+
+// RasterizePerspective is a perspective-correct sibling of Rasterize. Each
+// vertex carries a homogeneous w (the clip-space coordinate from
+// projection); u/w, v/w and 1/w are interpolated linearly - the quantities
+// that vary linearly in screen space - and divided back out at every pixel
+// so textures no longer warp under perspective, unlike plain affine u, v
+// interpolation.
+func RasterizePerspective(ps PixelShader, x0, y0, x1, y1, x2, y2 int, u0, v0, w0, u1, v1, w1, u2, v2, w2 float32) {
+	// Reference: http://www.xbdev.net/maths_of_3d/rasterization/texturedtriangle/index.php
+
+	multiSwapPersp(&x0, &y0, &x1, &y1, &x2, &y2, &u0, &v0, &w0, &u1, &v1, &w1, &u2, &v2, &w2)
+
+	// From here on u, v and w stand for u/w, v/w and 1/w - the attributes
+	// that are actually affine in screen space.
+	iw0, iw1, iw2 := 1/w0, 1/w1, 1/w2
+	u0, v0 = u0*iw0, v0*iw0
+	u1, v1 = u1*iw1, v1*iw1
+	u2, v2 = u2*iw2, v2*iw2
+	w0, w1, w2 = iw0, iw1, iw2
+
+	dxdy1 := float32(x2 - x0)
+	dxdu1 := u2 - u0
+	dxdv1 := v2 - v0
+	dxdw1 := w2 - w0
+
+	dxdy2 := float32(x1 - x0)
+	dxdu2 := u1 - u0
+	dxdv2 := v1 - v0
+	dxdw2 := w1 - w0
+
+	var (
+		sdx, sdu, sdv, sdw,
+		edx, edu, edv, edw,
+		pu, pv, pw float32
+	)
+
+	dy1 := float32(y2 - y0)
+	dy2 := float32(y1 - y0)
+
+	if y2-y0 != 0 {
+		dxdy1 /= dy1
+		dxdu1 /= dy1
+		dxdv1 /= dy1
+		dxdw1 /= dy1
+	}
+
+	if y1-y0 != 0 {
+		dxdy2 /= dy2
+		dxdu2 /= dy2
+		dxdv2 /= dy2
+		dxdw2 /= dy2
+	}
+
+	var (
+		dxldy, dxrdy,
+		dxldu, dxrdu,
+		dxldv, dxrdv,
+		dxldw, dxrdw float32
+	)
+
+	if dxdy1 < dxdy2 {
+		dxldy, dxrdy = dxdy1, dxdy2
+		dxldu, dxrdu = dxdu1, dxdu2
+		dxldv, dxrdv = dxdv1, dxdv2
+		dxldw, dxrdw = dxdw1, dxdw2
+	} else {
+		dxldy, dxrdy = dxdy2, dxdy1
+		dxldu, dxrdu = dxdu2, dxdu1
+		dxldv, dxrdv = dxdv2, dxdv1
+		dxldw, dxrdw = dxdw2, dxdw1
+	}
+
+	sdx, sdu, sdv, sdw = float32(x0), u0, v0, w0
+	edx, edu, edv, edw = float32(x0), u0, v0, w0
+
+	var pDeltaU, pDeltaV, pDeltaW float32
+
+	for y := y0; y <= y2; y++ {
+		pDeltaU = edu - sdu
+		pDeltaV = edv - sdv
+		pDeltaW = edw - sdw
+
+		if edx-sdx != 0 {
+			pDeltaU /= edx - sdx
+			pDeltaV /= edx - sdx
+			pDeltaW /= edx - sdx
+		}
+
+		pu, pv, pw = sdu, sdv, sdw
+
+		for x := int(sdx); x <= int(edx); x++ {
+			if pw != 0 {
+				ps(x, y, pu/pw, pv/pw)
+			}
+			pu += pDeltaU
+			pv += pDeltaV
+			pw += pDeltaW
+		}
+
+		sdx += dxldy
+		sdu += dxldu
+		sdv += dxldv
+		sdw += dxldw
+		edx += dxrdy
+		edu += dxrdu
+		edv += dxrdv
+		edw += dxrdw
+	}
+
+	// Render bottom part of triangle.
+
+	if dxdy1 < dxdy2 {
+		dxldy = float32(x1 - x2)
+		dxldu = u1 - u2
+		dxldv = v1 - v2
+		dxldw = w1 - w2
+
+		if y1-y2 != 0 {
+			dxldy /= float32(y1 - y2)
+			dxldu /= float32(y1 - y2)
+			dxldv /= float32(y1 - y2)
+			dxldw /= float32(y1 - y2)
+		}
+
+		sdx, sdu, sdv, sdw = float32(x2), u2, v2, w2
+	} else {
+		dxrdy = float32(x1 - x2)
+		dxrdu = u1 - u2
+		dxrdv = v1 - v2
+		dxrdw = w1 - w2
+
+		if y1-y2 != 0 {
+			dxrdy /= float32(y1 - y2)
+			dxrdu /= float32(y1 - y2)
+			dxrdv /= float32(y1 - y2)
+			dxrdw /= float32(y1 - y2)
+		}
+
+		edx, edu, edv, edw = float32(x2), u2, v2, w2
+	}
+
+	for y := y2; y <= y1; y++ {
+		pDeltaU = edu - sdu
+		pDeltaV = edv - sdv
+		pDeltaW = edw - sdw
+
+		if edx-sdx != 0 {
+			pDeltaU /= edx - sdx
+			pDeltaV /= edx - sdx
+			pDeltaW /= edx - sdx
+		}
+
+		pu, pv, pw = sdu, sdv, sdw
+
+		for x := int(sdx); x <= int(edx); x++ {
+			if pw != 0 {
+				ps(x, y, pu/pw, pv/pw)
+			}
+			pu += pDeltaU
+			pv += pDeltaV
+			pw += pDeltaW
+		}
+
+		sdx += dxldy
+		sdu += dxldu
+		sdv += dxldv
+		sdw += dxldw
+		edx += dxrdy
+		edu += dxrdu
+		edv += dxrdv
+		edw += dxrdw
+	}
+}
@@ -0,0 +1,43 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "testing"
+
+// TestRasterizePerspectiveMatchesAffineAtUnitW covers that, with every
+// vertex's w set to 1 (i.e. no actual perspective), RasterizePerspective's
+// u/w, v/w, 1/w interpolation reduces to plain affine interpolation and so
+// should sample the same (u, v) as Rasterize pixel for pixel.
+func TestRasterizePerspectiveMatchesAffineAtUnitW(t *testing.T) {
+	x0, y0 := 0, 0
+	x1, y1 := 10, 30
+	x2, y2 := 30, 10
+	u0, v0 := float32(0), float32(0)
+	u1, v1 := float32(1), float32(0)
+	u2, v2 := float32(0), float32(1)
+
+	type sample struct{ u, v float32 }
+	affine := map[[2]int]sample{}
+	Rasterize(func(x, y int, u, v float32) {
+		affine[[2]int{x, y}] = sample{u, v}
+	}, x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2)
+
+	const tol = 1.0 / 16
+	var checked int
+	RasterizePerspective(func(x, y int, u, v float32) {
+		a, ok := affine[[2]int{x, y}]
+		if !ok {
+			return
+		}
+		checked++
+		if absF32(u-a.u) > tol || absF32(v-a.v) > tol {
+			t.Errorf("pixel (%d,%d): perspective got u=%v v=%v, affine got u=%v v=%v", x, y, u, v, a.u, a.v)
+		}
+	}, x0, y0, x1, y1, x2, y2, u0, v0, 1, u1, v1, 1, u2, v2, 1)
+
+	if checked == 0 {
+		t.Fatal("expected overlapping pixels between Rasterize and RasterizePerspective")
+	}
+}
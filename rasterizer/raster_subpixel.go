@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "math"
+
+// multiSwapSubpixel sorts the three vertices into y0 <= y1 <= y2, which is
+// the order RasterizeSubpixel's single pass over scanlines expects: it walks
+// from y0 to y2, switching from the (x0,y0)-(x1,y1) edge to the
+// (x1,y1)-(x2,y2) edge once it passes y1. Note this is the opposite of
+// multiSwap's y0 <= y2 <= y1, which the two-pass fixed/float backends need
+// instead.
+func multiSwapSubpixel(x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2 *float32) {
+	if *y1 < *y0 {
+		swapFloat32(y1, y0)
+		swapFloat32(x1, x0)
+		swapFloat32(u1, u0)
+		swapFloat32(v1, v0)
+	}
+
+	if *y2 < *y0 {
+		swapFloat32(y2, y0)
+		swapFloat32(x2, x0)
+		swapFloat32(u2, u0)
+		swapFloat32(v2, v0)
+	}
+
+	if *y2 < *y1 {
+		swapFloat32(y2, y1)
+		swapFloat32(x2, x1)
+		swapFloat32(u2, u1)
+		swapFloat32(v2, v1)
+	}
+}
+
+// edgeAt linearly interpolates x, u and v along the edge (x0,y0)-(x1,y1) at
+// height y. y is not clamped to the edge's own span: a y outside [y0, y1]
+// extrapolates along the edge's line.
+func edgeAt(y, x0, y0, u0, v0, x1, y1, u1, v1 float32) (x, u, v float32) {
+	if y1 == y0 {
+		return x0, u0, v0
+	}
+
+	t := (y - y0) / (y1 - y0)
+	return x0 + t*(x1-x0), u0 + t*(u1-u0), v0 + t*(v1-v0)
+}
+
+// RasterizeSubpixel is a sibling of Rasterize that takes float32 vertex
+// coordinates instead of integers. Only the outer y loop is snapped to
+// integer scanlines; the left and right edges are re-projected to each
+// scanline's y+0.5 center as fractional floats, so animated triangles sweep
+// smoothly across pixels instead of visibly snapping to them, and adjacent
+// triangles that share an edge neither crack nor double-shade the seam.
+func RasterizeSubpixel(ps PixelShader, x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2 float32) {
+	multiSwapSubpixel(&x0, &y0, &x1, &y1, &x2, &y2, &u0, &v0, &u1, &v1, &u2, &v2)
+
+	if y0 == y2 {
+		return
+	}
+
+	yStart := int(math.Ceil(float64(y0 - 0.5)))
+	yEnd := int(math.Ceil(float64(y2-0.5))) - 1
+
+	for y := yStart; y <= yEnd; y++ {
+		sy := float32(y) + 0.5
+
+		xA, uA, vA := edgeAt(sy, x0, y0, u0, v0, x2, y2, u2, v2)
+
+		var xB, uB, vB float32
+		if sy < y1 {
+			xB, uB, vB = edgeAt(sy, x0, y0, u0, v0, x1, y1, u1, v1)
+		} else {
+			xB, uB, vB = edgeAt(sy, x1, y1, u1, v1, x2, y2, u2, v2)
+		}
+
+		left, right := xA, xB
+		uLeft, vLeft := uA, vA
+		uRight, vRight := uB, vB
+		if left > right {
+			left, right = right, left
+			uLeft, uRight = uRight, uLeft
+			vLeft, vRight = vRight, vLeft
+		}
+
+		// Half-open [left, right) on pixel centers: a pixel whose center sits
+		// exactly on a shared edge belongs to the triangle on its right, not
+		// both, which is what keeps a seam between two triangles from being
+		// double-shaded.
+		xStart := int(math.Ceil(float64(left - 0.5)))
+		xEnd := int(math.Ceil(float64(right-0.5))) - 1
+
+		span := right - left
+		for x := xStart; x <= xEnd; x++ {
+			var t float32
+			if span != 0 {
+				t = (float32(x) + 0.5 - left) / span
+			}
+			ps(x, y, uLeft+t*(uRight-uLeft), vLeft+t*(vRight-vLeft))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "testing"
+
+// TestRasterizeSubpixelNoCrackOrOverlap covers the claim that two triangles
+// sharing an edge neither crack (leave unshaded pixels along the seam) nor
+// double-shade it, by splitting a square into two triangles along its
+// diagonal and counting how many times each pixel center gets shaded.
+func TestRasterizeSubpixelNoCrackOrOverlap(t *testing.T) {
+	const size = 20
+
+	counts := make(map[[2]int]int)
+	record := func(x, y int, u, v float32) {
+		counts[[2]int{x, y}]++
+	}
+
+	RasterizeSubpixel(record, 0, 0, size, 0, 0, size, 0, 0, 1, 0, 0, 1)
+	RasterizeSubpixel(record, size, 0, size, size, 0, size, 0, 0, 1, 0, 0, 1)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if n := counts[[2]int{x, y}]; n != 1 {
+				t.Errorf("pixel (%d,%d) shaded %d times, want exactly 1", x, y, n)
+			}
+		}
+	}
+}
+
+// TestRasterizeSubpixelMatchesRasterizeOnIntegerCoords covers that, for a
+// triangle whose vertices already sit on integer coordinates, the subpixel
+// backend samples the same (u, v) as the integer Rasterize backend.
+func TestRasterizeSubpixelMatchesRasterizeOnIntegerCoords(t *testing.T) {
+	x0, y0 := 2, 3
+	x1, y1 := 18, 25
+	x2, y2 := 30, 6
+
+	type sample struct{ u, v float32 }
+	want := map[[2]int]sample{}
+	Rasterize(func(x, y int, u, v float32) {
+		want[[2]int{x, y}] = sample{u, v}
+	}, x0, y0, x1, y1, x2, y2, 0, 0, 1, 0, 0, 1)
+
+	const tol = 1.0 / 16
+	var checked int
+	RasterizeSubpixel(func(x, y int, u, v float32) {
+		w, ok := want[[2]int{x, y}]
+		if !ok {
+			return
+		}
+		checked++
+		if absF32(u-w.u) > tol || absF32(v-w.v) > tol {
+			t.Errorf("pixel (%d,%d): subpixel got u=%v v=%v, want u=%v v=%v", x, y, u, v, w.u, w.v)
+		}
+	}, float32(x0), float32(y0), float32(x1), float32(y1), float32(x2), float32(y2), 0, 0, 1, 0, 0, 1)
+
+	if checked == 0 {
+		t.Fatal("expected overlapping pixels between Rasterize and RasterizeSubpixel")
+	}
+}
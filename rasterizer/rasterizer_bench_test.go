@@ -0,0 +1,46 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "testing"
+
+func noopShader(x, y int, u, v float32) {}
+
+// BenchmarkRasterizeFixedSmall and BenchmarkRasterizeFloatSmall (64 px
+// extent) sit below fixedPointMathThreshold, and the Large pair (1024 px)
+// above it. Contrary to the original assumption behind the split, repeated
+// runs (`go test -bench=Small -count=5`) show no reliable speed difference
+// between the two backends at the Small size — the split exists for the
+// fixed-point backend's 26.6 slope precision past the threshold, not for
+// speed.
+func BenchmarkRasterizeFixedSmall(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		rasterizeFixed(noopShader, 0, 0, 64, 32, 32, 64, 0, 0, 1, 0, 0, 1)
+	}
+}
+
+func BenchmarkRasterizeFloatSmall(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		rasterizeFloat(noopShader, 0, 0, 64, 32, 32, 64, 0, 0, 1, 0, 0, 1)
+	}
+}
+
+func BenchmarkRasterizeFixedLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		rasterizeFixed(noopShader, 0, 0, 1024, 512, 512, 1024, 0, 0, 1, 0, 0, 1)
+	}
+}
+
+func BenchmarkRasterizeFloatLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		rasterizeFloat(noopShader, 0, 0, 1024, 512, 512, 1024, 0, 0, 1, 0, 0, 1)
+	}
+}
+
+func BenchmarkRasterizeDispatch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Rasterize(noopShader, 0, 0, 64, 32, 32, 64, 0, 0, 1, 0, 0, 1)
+	}
+}
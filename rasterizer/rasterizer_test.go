@@ -0,0 +1,40 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import "testing"
+
+// TestBackendsAgree covers a regression where rasterizeFloat computed
+// dxdu2 as u1-v0 instead of u1-u0, a copy-paste typo that made the float
+// and fixed-point backends interpolate different UVs for the same
+// triangle even though Rasterize's dispatcher picks between them based on
+// nothing but triangle size.
+func TestBackendsAgree(t *testing.T) {
+	const tol = 1.0 / 32
+
+	x0, y0 := 2, 3
+	x1, y1 := 40, 60
+	x2, y2 := 70, 10
+	u0, v0 := float32(0), float32(0)
+	u1, v1 := float32(1), float32(0)
+	u2, v2 := float32(0.5), float32(1)
+
+	type sample struct{ u, v float32 }
+	floatSamples := map[[2]int]sample{}
+
+	rasterizeFloat(func(x, y int, u, v float32) {
+		floatSamples[[2]int{x, y}] = sample{u, v}
+	}, x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2)
+
+	rasterizeFixed(func(x, y int, u, v float32) {
+		fs, ok := floatSamples[[2]int{x, y}]
+		if !ok {
+			return
+		}
+		if absF32(u-fs.u) > tol || absF32(v-fs.v) > tol {
+			t.Errorf("pixel (%d,%d): fixed backend got u=%v v=%v, float backend got u=%v v=%v", x, y, u, v, fs.u, fs.v)
+		}
+	}, x0, y0, x1, y1, x2, y2, u0, v0, u1, v1, u2, v2)
+}
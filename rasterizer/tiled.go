@@ -0,0 +1,146 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import (
+	"image"
+	"sync"
+)
+
+// defaultTileSize is the width and height, in pixels, of each tile a
+// TiledRasterizer hands to a worker goroutine.
+const defaultTileSize = 64
+
+type triangleJob struct {
+	ps                     PixelShader
+	x0, y0, x1, y1, x2, y2 int
+	u0, v0, u1, v1, u2, v2 float32
+}
+
+// TiledRasterizer partitions a target image into fixed-size tiles, each
+// owned by its own worker goroutine, and dispatches every triangle to
+// whichever tiles its bounding box overlaps. Because a tile is only ever
+// touched by its own worker, no locking is needed around the underlying
+// draw.Image.Set calls.
+type TiledRasterizer struct {
+	bounds         image.Rectangle
+	tileSize       int
+	tilesX, tilesY int
+	queues         []chan triangleJob
+	wg             sync.WaitGroup
+}
+
+// NewTiledRasterizer returns a TiledRasterizer covering bounds, using
+// defaultTileSize tiles.
+func NewTiledRasterizer(bounds image.Rectangle) *TiledRasterizer {
+	return NewTiledRasterizerSize(bounds, defaultTileSize)
+}
+
+// NewTiledRasterizerSize is like NewTiledRasterizer but lets the caller pick
+// the tile size.
+func NewTiledRasterizerSize(bounds image.Rectangle, tileSize int) *TiledRasterizer {
+	if tileSize <= 0 {
+		tileSize = defaultTileSize
+	}
+
+	tr := &TiledRasterizer{
+		bounds:   bounds,
+		tileSize: tileSize,
+		tilesX:   (bounds.Dx() + tileSize - 1) / tileSize,
+		tilesY:   (bounds.Dy() + tileSize - 1) / tileSize,
+	}
+
+	tr.queues = make([]chan triangleJob, tr.tilesX*tr.tilesY)
+	for i := range tr.queues {
+		q := make(chan triangleJob, 64)
+		tr.queues[i] = q
+
+		tr.wg.Add(1)
+		go tr.worker(q)
+	}
+
+	return tr
+}
+
+func (tr *TiledRasterizer) worker(jobs <-chan triangleJob) {
+	defer tr.wg.Done()
+	for j := range jobs {
+		Rasterize(j.ps, j.x0, j.y0, j.x1, j.y1, j.x2, j.y2, j.u0, j.v0, j.u1, j.v1, j.u2, j.v2)
+	}
+}
+
+func (tr *TiledRasterizer) tileBounds(tx, ty int) image.Rectangle {
+	x0 := tr.bounds.Min.X + tx*tr.tileSize
+	y0 := tr.bounds.Min.Y + ty*tr.tileSize
+	x1 := x0 + tr.tileSize
+	y1 := y0 + tr.tileSize
+
+	if x1 > tr.bounds.Max.X {
+		x1 = tr.bounds.Max.X
+	}
+	if y1 > tr.bounds.Max.Y {
+		y1 = tr.bounds.Max.Y
+	}
+
+	return image.Rect(x0, y0, x1, y1)
+}
+
+func clampTileIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// clipShader wraps ps so that writes outside r are silently discarded,
+// keeping a tile's worker from touching pixels another tile's worker owns.
+func clipShader(ps PixelShader, r image.Rectangle) PixelShader {
+	return func(x, y int, u, v float32) {
+		if (image.Point{X: x, Y: y}).In(r) {
+			ps(x, y, u, v)
+		}
+	}
+}
+
+// Draw queues a triangle for rasterization on every tile its bounding box
+// overlaps. It does not wait for rasterization, but it blocks while a
+// target tile's queue already holds 64 pending triangles; call Flush to wait
+// for the work to finish.
+func (tr *TiledRasterizer) Draw(ps PixelShader, x0, y0, x1, y1, x2, y2 int, u0, v0, u1, v1, u2, v2 float32) {
+	if tr.tilesX == 0 || tr.tilesY == 0 {
+		return
+	}
+
+	minX, maxX := min3(x0, x1, x2), max3(x0, x1, x2)
+	minY, maxY := min3(y0, y1, y2), max3(y0, y1, y2)
+
+	tileMinX := clampTileIndex((minX-tr.bounds.Min.X)/tr.tileSize, tr.tilesX)
+	tileMaxX := clampTileIndex((maxX-tr.bounds.Min.X)/tr.tileSize, tr.tilesX)
+	tileMinY := clampTileIndex((minY-tr.bounds.Min.Y)/tr.tileSize, tr.tilesY)
+	tileMaxY := clampTileIndex((maxY-tr.bounds.Min.Y)/tr.tileSize, tr.tilesY)
+
+	for ty := tileMinY; ty <= tileMaxY; ty++ {
+		for tx := tileMinX; tx <= tileMaxX; tx++ {
+			clipped := clipShader(ps, tr.tileBounds(tx, ty))
+			tr.queues[ty*tr.tilesX+tx] <- triangleJob{
+				ps: clipped,
+				x0: x0, y0: y0, x1: x1, y1: y1, x2: x2, y2: y2,
+				u0: u0, v0: v0, u1: u1, v1: v1, u2: u2, v2: v2,
+			}
+		}
+	}
+}
+
+// Flush waits for every queued triangle to finish rasterizing. Once called,
+// the TiledRasterizer can no longer accept further Draw calls.
+func (tr *TiledRasterizer) Flush() {
+	for _, q := range tr.queues {
+		close(q)
+	}
+	tr.wg.Wait()
+}
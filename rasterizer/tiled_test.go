@@ -0,0 +1,92 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rasterizer
+
+import (
+	"image"
+	"sync"
+	"testing"
+)
+
+// TestTiledRasterizerMatchesRasterize covers that splitting the target into
+// tiles and dispatching triangles to worker goroutines produces the same
+// per-pixel output as calling Rasterize directly, including for a triangle
+// that spans several tiles and so is queued on more than one of them.
+func TestTiledRasterizerMatchesRasterize(t *testing.T) {
+	bounds := image.Rect(0, 0, 128, 128)
+	x0, y0 := 10, 10
+	x1, y1 := 20, 100
+	x2, y2 := 110, 40
+
+	type sample struct{ u, v float32 }
+	want := map[[2]int]sample{}
+	var wantMu sync.Mutex
+	Rasterize(func(x, y int, u, v float32) {
+		wantMu.Lock()
+		want[[2]int{x, y}] = sample{u, v}
+		wantMu.Unlock()
+	}, x0, y0, x1, y1, x2, y2, 0, 0, 1, 0, 0, 1)
+
+	got := map[[2]int]sample{}
+	var gotMu sync.Mutex
+
+	tr := NewTiledRasterizerSize(bounds, 32)
+	tr.Draw(func(x, y int, u, v float32) {
+		gotMu.Lock()
+		got[[2]int{x, y}] = sample{u, v}
+		gotMu.Unlock()
+	}, x0, y0, x1, y1, x2, y2, 0, 0, 1, 0, 0, 1)
+	tr.Flush()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d shaded pixels, want %d", len(got), len(want))
+	}
+
+	const tol = 1.0 / 16
+	for p, w := range want {
+		g, ok := got[p]
+		if !ok {
+			t.Errorf("pixel %v missing from tiled output", p)
+			continue
+		}
+		if absF32(g.u-w.u) > tol || absF32(g.v-w.v) > tol {
+			t.Errorf("pixel %v: tiled got u=%v v=%v, want u=%v v=%v", p, g.u, g.v, w.u, w.v)
+		}
+	}
+}
+
+// TestTiledRasterizerNoDoubleShade covers that a triangle whose bounding box
+// spans several tiles gets each of its pixels shaded exactly as many times
+// as a direct, untiled Rasterize call would shade it: tiling must not add
+// extra shader calls of its own by handing the same pixel to more than one
+// tile's worker.
+func TestTiledRasterizerNoDoubleShade(t *testing.T) {
+	bounds := image.Rect(0, 0, 128, 128)
+	x0, y0 := 10, 10
+	x1, y1 := 20, 100
+	x2, y2 := 110, 40
+
+	want := map[[2]int]int{}
+	Rasterize(func(x, y int, u, v float32) {
+		want[[2]int{x, y}]++
+	}, x0, y0, x1, y1, x2, y2, 0, 0, 1, 0, 0, 1)
+
+	got := map[[2]int]int{}
+	var mu sync.Mutex
+
+	tr := NewTiledRasterizerSize(bounds, 32)
+	tr.Draw(func(x, y int, u, v float32) {
+		mu.Lock()
+		got[[2]int{x, y}]++
+		mu.Unlock()
+	}, x0, y0, x1, y1, x2, y2, 0, 0, 1, 0, 0, 1)
+	tr.Flush()
+
+	for p, n := range got {
+		if n != want[p] {
+			t.Errorf("pixel %v shaded %d times, want %d (direct Rasterize count)", p, n, want[p])
+		}
+	}
+}